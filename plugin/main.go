@@ -5,10 +5,11 @@ import (
 	"os"
 
 	"myapp/todo"
+	"myapp/todo/resolvergen"
 
 	"github.com/99designs/gqlgen/api"
 	"github.com/99designs/gqlgen/codegen/config"
-	
+
 )
 
 
@@ -19,7 +20,13 @@ func main() {
 		os.Exit(2)
 	}
 
-	err = api.Generate(cfg,api.NoPlugins(), api.AddPlugin(todo.New()))
+	modelPlugin := todo.New().(*todo.Plugin)
+
+	err = api.Generate(cfg,
+		api.NoPlugins(),
+		api.AddPlugin(modelPlugin),
+		api.AddPlugin(resolvergen.New(modelPlugin)),
+	)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(3)
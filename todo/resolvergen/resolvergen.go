@@ -0,0 +1,133 @@
+package resolvergen
+
+import (
+	"fmt"
+	"text/template"
+
+	"myapp/todo"
+
+	"github.com/99designs/gqlgen/codegen/config"
+	"github.com/99designs/gqlgen/codegen/templates"
+	"github.com/99designs/gqlgen/plugin"
+)
+
+// Plugin generates GORM-backed CRUD resolver stubs for every @gormModel
+// type. It reuses the sibling model-generation plugin's ModelBuild instead
+// of re-deriving field information from the schema, so the two plugins can
+// never disagree about a field's Go name or type.
+type Plugin struct {
+	Models *todo.Plugin
+	// Filename is where the generated resolver stubs are written.
+	Filename string
+}
+
+// New wires a resolver-stub plugin to the model plugin it should read
+// field and type information from. Pass the same *todo.Plugin instance
+// registered with api.AddPlugin so both plugins build from one ModelBuild.
+func New(models *todo.Plugin) plugin.Plugin {
+	return &Plugin{
+		Models:   models,
+		Filename: "resolvergen/resolver_gen.go",
+	}
+}
+
+var _ plugin.ConfigMutator = &Plugin{}
+
+func (p *Plugin) Name() string {
+	return "todo-resolvergen"
+}
+
+// entity pairs a database-backed Object with the field the CRUD stubs
+// should key lookups and deletes on.
+type entity struct {
+	*todo.Object
+	PKField *todo.Field
+}
+
+// resolverData is the root template context: the import path the model
+// types live under, plus the entities to emit stubs for.
+type resolverData struct {
+	ModelImportPath string
+	Entities        []entity
+}
+
+func (p *Plugin) MutateConfig(cfg *config.Config) error {
+	b, err := p.Models.Build(cfg)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return nil
+	}
+
+	data := resolverData{ModelImportPath: cfg.Model.ImportPath()}
+	for _, o := range b.Models {
+		if !o.IsGormModel {
+			continue
+		}
+		if len(o.Fields) == 0 {
+			return fmt.Errorf("gorm model %s has no fields to key CRUD stubs on", o.Name)
+		}
+		data.Entities = append(data.Entities, entity{Object: o, PKField: todo.PrimaryKeyField(o)})
+	}
+	if len(data.Entities) == 0 {
+		return nil
+	}
+
+	return templates.Render(templates.Options{
+		PackageName:     "resolvergen",
+		Filename:        p.Filename,
+		Data:            data,
+		GeneratedHeader: true,
+		Packages:        cfg.Packages,
+		Funcs: template.FuncMap{
+			"gormColumn": todo.GormColumn,
+		},
+		Template: `
+			{{ reserveImport "context" }}
+			{{ reserveImport "gorm.io/gorm" }}
+			{{ $model := lookupImport .ModelImportPath }}
+
+			{{ range $entity := .Entities }}
+				// {{ $entity.Name|go }}Resolver provides GORM-backed CRUD access for {{ $entity.Name|go }}.
+				type {{ $entity.Name|go }}Resolver struct {
+					DB *gorm.DB
+				}
+
+				func (r *{{ $entity.Name|go }}Resolver) FindByID(ctx context.Context, id {{ $entity.PKField.Type | ref }}) (*{{$model}}.{{ $entity.Name|go }}, error) {
+					var out {{$model}}.{{ $entity.Name|go }}
+					if err := r.DB.WithContext(ctx).First(&out, "{{ $entity.PKField | gormColumn }} = ?", id).Error; err != nil {
+						return nil, err
+					}
+					return &out, nil
+				}
+
+				func (r *{{ $entity.Name|go }}Resolver) List(ctx context.Context) ([]*{{$model}}.{{ $entity.Name|go }}, error) {
+					var out []*{{$model}}.{{ $entity.Name|go }}
+					if err := r.DB.WithContext(ctx).Find(&out).Error; err != nil {
+						return nil, err
+					}
+					return out, nil
+				}
+
+				func (r *{{ $entity.Name|go }}Resolver) Create(ctx context.Context, input *{{$model}}.{{ $entity.Name|go }}) (*{{$model}}.{{ $entity.Name|go }}, error) {
+					if err := r.DB.WithContext(ctx).Create(input).Error; err != nil {
+						return nil, err
+					}
+					return input, nil
+				}
+
+				func (r *{{ $entity.Name|go }}Resolver) Update(ctx context.Context, input *{{$model}}.{{ $entity.Name|go }}) (*{{$model}}.{{ $entity.Name|go }}, error) {
+					if err := r.DB.WithContext(ctx).Save(input).Error; err != nil {
+						return nil, err
+					}
+					return input, nil
+				}
+
+				func (r *{{ $entity.Name|go }}Resolver) Delete(ctx context.Context, id {{ $entity.PKField.Type | ref }}) error {
+					return r.DB.WithContext(ctx).Delete(&{{$model}}.{{ $entity.Name|go }}{}, "{{ $entity.PKField | gormColumn }} = ?", id).Error
+				}
+			{{ end }}
+		`,
+	})
+}
@@ -2,8 +2,15 @@ package todo
 
 import (
 	"fmt"
+	"go/token"
 	"go/types"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
 
 	"github.com/99designs/gqlgen/codegen/config"
 	"github.com/99designs/gqlgen/codegen/templates"
@@ -13,16 +20,52 @@ import (
 
 type BuildMutateHook = func(b *ModelBuild) *ModelBuild
 
+// FieldMutateHook lets a downstream caller rewrite (or reject) a field after
+// the plugin has derived its type and base tags from the schema, but before
+// it is attached to its parent Object.
+type FieldMutateHook = func(td *ast.Definition, fd *ast.FieldDefinition, f *Field) (*Field, error)
+
+// ModelMutateHook lets a downstream caller rewrite (or reject) an Object
+// after all of its fields have been built, but before it is added to the
+// ModelBuild.
+type ModelMutateHook = func(td *ast.Definition, o *Object) (*Object, error)
+
 func defaultBuildMutateHook(b *ModelBuild) *ModelBuild {
 	return b
 }
 
+func defaultFieldMutateHook(td *ast.Definition, fd *ast.FieldDefinition, f *Field) (*Field, error) {
+	return f, nil
+}
+
+func defaultModelMutateHook(td *ast.Definition, o *Object) (*Object, error) {
+	return o, nil
+}
+
 type ModelBuild struct {
 	PackageName string
 	Interfaces  []*Interface
 	Models      []*Object
 	Enums       []*Enum
 	Scalars     []string
+	// Relations holds one entry per field whose type is another
+	// @isDatabaseField object (or a list of one), used to generate
+	// batching dataloaders in loaders_gen.go.
+	Relations []*Relation
+}
+
+// Relation describes a field whose type is another @isDatabaseField object,
+// eligible for a generated dataloader keyed on the target's primary key.
+type Relation struct {
+	Owner    string // schema name of the object declaring the relation field
+	Field    string // schema name (or configured field name) of the relation field
+	Target   *Object
+	PKType   types.Type
+	PKGoName string
+	PKColumn string
+	IsList   bool
+	Batch    int
+	Wait     time.Duration
 }
 
 type Interface struct {
@@ -35,14 +78,81 @@ type Object struct {
 	Name        string
 	Fields      []*Field
 	Implements  []string
+	// TableName is the explicit table name declared via @gormModel(tableName: ...).
+	// When empty, GORM falls back to its own pluralization convention.
+	TableName string
+	// SoftDelete marks that @gormModel(softDelete: true) was set, causing a
+	// gorm.DeletedAt field to be injected into the generated struct.
+	SoftDelete bool
+	// IsGormModel marks that the type carries a @gormModel directive at
+	// all, regardless of its arguments. Sibling plugins (e.g. resolvergen)
+	// use this to decide which types are database-backed.
+	IsGormModel bool
+	// Keys holds one entry per Apollo Federation @key(fields: "...") directive
+	// declared on the type, each entry being the ordered list of field names
+	// that make up that key (more than one for a compound key).
+	Keys [][]string
 }
 
+// Field holds everything needed to render one struct field, including its
+// struct tags. Tags are kept as a family name (json, gorm, validate, ...) to
+// value map rather than a single pre-rendered string so that directives
+// contributing to different tag families can be merged independently before
+// the field is rendered.
 type Field struct {
 	Description string
 	Name        string
 	Type        types.Type
-	Tag         string
-	Gorm        string
+	Tags        map[string]string
+	// LoaderHint, when set, is appended to the field's doc comment pointing
+	// resolvers at the generated dataloader to use instead of a direct query.
+	LoaderHint string
+}
+
+// tagOrder lists the well-known tag families in the order they should
+// appear on a rendered struct field. Any tag family not listed here is
+// appended afterwards, sorted alphabetically, so output stays deterministic.
+var tagOrder = []string{"json", "gorm", "validate"}
+
+// setTag records a tag family value on the field, creating the map on first use.
+func (f *Field) setTag(name, value string) {
+	if value == "" {
+		return
+	}
+	if f.Tags == nil {
+		f.Tags = map[string]string{}
+	}
+	f.Tags[name] = value
+}
+
+// renderTags composes a field's tag families into a single Go struct tag
+// string, e.g. `json:"id" gorm:"column:id;primaryKey"`.
+func renderTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	seen := make(map[string]bool, len(tagOrder))
+	var parts []string
+	for _, name := range tagOrder {
+		if v, ok := tags[name]; ok {
+			parts = append(parts, fmt.Sprintf(`%s:%q`, name, v))
+			seen[name] = true
+		}
+	}
+
+	var rest []string
+	for name := range tags {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		parts = append(parts, fmt.Sprintf(`%s:%q`, name, tags[name]))
+	}
+
+	return strings.Join(parts, " ")
 }
 
 type Enum struct {
@@ -57,12 +167,37 @@ type EnumValue struct {
 }
 
 type Plugin struct {
-	MutateHook BuildMutateHook
+	MutateHook      BuildMutateHook
+	FieldMutateHook FieldMutateHook
+	ModelMutateHook ModelMutateHook
+	// ModelsDir overrides where object/interface files are written, one
+	// file per type (e.g. models/user.go). Defaults to the directory of
+	// cfg.Model.Filename.
+	ModelsDir string
+	// EnumsDir overrides where enum files are written, one file per enum
+	// (e.g. enums/status.go). Defaults to ModelsDir.
+	EnumsDir string
+	// Dataloaders enables generating batching dataloaders (loaders_gen.go)
+	// for relation fields whose type is another @isDatabaseField object.
+	// Defaults to true; individual fields can still opt out via
+	// @dataloader(enable: false).
+	Dataloaders bool
+
+	// built caches the result of the first Build call so that sibling
+	// plugins (e.g. resolvergen) reading it a second time get the exact
+	// ModelBuild MutateConfig rendered from, instead of re-running the
+	// schema walk (and any FieldMutateHook/ModelMutateHook) a second time.
+	built    bool
+	buildRes *ModelBuild
+	buildErr error
 }
 
 func New() plugin.Plugin {
 	return &Plugin{
-		MutateHook: defaultBuildMutateHook,
+		MutateHook:      defaultBuildMutateHook,
+		FieldMutateHook: defaultFieldMutateHook,
+		ModelMutateHook: defaultModelMutateHook,
+		Dataloaders:     true,
 	}
 }
 
@@ -73,18 +208,50 @@ func (m *Plugin) Name() string {
 }
 
 func (m *Plugin) MutateConfig(cfg *config.Config) error {
+	b, err := m.Build(cfg)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return nil
+	}
+
+	return renderModels(cfg, b, m)
+}
+
+// Build derives a ModelBuild from the schema without rendering anything,
+// applying the same directive parsing and mutate hooks MutateConfig would.
+// Sibling plugins (e.g. resolvergen) call this to read field and model
+// information without re-deriving it from the schema themselves. The schema
+// walk only ever runs once per Plugin: the result (including any error) is
+// cached on the first call and replayed on every call after, so a
+// FieldMutateHook/ModelMutateHook with side effects runs exactly once and
+// every caller sees the same ModelBuild MutateConfig rendered from.
+func (m *Plugin) Build(cfg *config.Config) (*ModelBuild, error) {
+	if m.built {
+		return m.buildRes, m.buildErr
+	}
+	m.buildRes, m.buildErr = m.build(cfg)
+	m.built = true
+	return m.buildRes, m.buildErr
+}
+
+// build performs the actual schema walk for Build, uncached.
+func (m *Plugin) build(cfg *config.Config) (*ModelBuild, error) {
 	binder := cfg.NewBinder()
 
 	b := &ModelBuild{
 		PackageName: cfg.Model.Package,
 	}
 
+	var pendingRelations []pendingRelation
+
 	for _, schemaType := range cfg.Schema.Types {
 
 		if schemaType.BuiltIn {
 			continue
 		}
-		
+
 		switch schemaType.Kind {
 		case ast.Interface, ast.Union:
 			it := &Interface{
@@ -105,6 +272,27 @@ func (m *Plugin) MutateConfig(cfg *config.Config) error {
 				it.Implements = append(it.Implements, implementor.Name)
 			}
 
+			if gormModel := schemaType.Directives.ForName("gormModel"); gormModel != nil {
+				it.IsGormModel = true
+				if arg := gormModel.Arguments.ForName("tableName"); arg != nil {
+					it.TableName = arg.Value.Raw
+				}
+				if arg := gormModel.Arguments.ForName("softDelete"); arg != nil && arg.Value.Raw == "true" {
+					it.SoftDelete = true
+				}
+			}
+
+			for _, keyDirective := range schemaType.Directives {
+				if keyDirective.Name != "key" {
+					continue
+				}
+				arg := keyDirective.Arguments.ForName("fields")
+				if arg == nil {
+					continue
+				}
+				it.Keys = append(it.Keys, strings.Fields(arg.Value.Raw))
+			}
+
 			for _, field := range schemaType.Fields {
 				var typ types.Type
 				fieldDef := cfg.Schema.Types[field.Type.Name()]
@@ -113,7 +301,7 @@ func (m *Plugin) MutateConfig(cfg *config.Config) error {
 					var err error
 					typ, err = binder.FindTypeFromName(cfg.Models[field.Type.Name()].Model[0])
 					if err != nil {
-						return err
+						return nil, err
 					}
 				} else {
 					switch fieldDef.Kind {
@@ -165,24 +353,73 @@ func (m *Plugin) MutateConfig(cfg *config.Config) error {
 					typ = types.NewPointer(typ)
 				}
 
-				gormType := ""
-				directive := field.Directives.ForName("isDatabaseField")
-				if directive != nil {
-					arg := directive.Arguments.ForName("fieldName")
-					if arg != nil {
-						gormType = fmt.Sprintf(`gorm:"column:%s"`, arg.Value.Raw)
-					}else {
-						gormType = fmt.Sprintf(`gorm:"column:%s"`, field.Name)
-					}					
-				}
-
-				it.Fields = append(it.Fields, &Field{
+				f := &Field{
 					Name:        name,
 					Type:        typ,
 					Description: field.Description,
-					Tag:         `json:"` + field.Name + `"`,
-					Gorm:        gormType,
-				})
+				}
+				f.setTag("json", field.Name)
+				f.setTag("gorm", gormTagFromDirective(field.Name, field.Directives.ForName("isDatabaseField")))
+				applyGoTagDirectives(f, field)
+
+				if m.FieldMutateHook != nil {
+					var err error
+					f, err = m.FieldMutateHook(schemaType, field, f)
+					if err != nil {
+						return nil, fmt.Errorf("generating field %s.%s: %w", schemaType.Name, field.Name, err)
+					}
+				}
+
+				if m.Dataloaders {
+					if targetName, isList, ok := relationTarget(fieldDef, field); ok {
+						enabled, batchSize, wait, err := dataloaderDirective(field)
+						if err != nil {
+							return nil, fmt.Errorf("generating field %s.%s: %w", schemaType.Name, field.Name, err)
+						}
+						if enabled {
+							pendingRelations = append(pendingRelations, pendingRelation{
+								owner:      it,
+								field:      f,
+								targetName: targetName,
+								isList:     isList,
+								batch:      batchSize,
+								wait:       wait,
+							})
+						}
+					}
+				}
+
+				it.Fields = append(it.Fields, f)
+			}
+
+			for _, key := range it.Keys {
+				for _, keyField := range key {
+					if fieldByGraphQLName(it, keyField) == nil {
+						return nil, fmt.Errorf("todo: @key field %q on type %s does not match any field", keyField, it.Name)
+					}
+				}
+			}
+
+			if it.SoftDelete {
+				deletedAt := &Field{
+					Name: "DeletedAt",
+					Type: types.NewNamed(
+						types.NewTypeName(0, cfg.Model.Pkg(), "gorm.DeletedAt", nil),
+						nil,
+						nil,
+					),
+				}
+				deletedAt.setTag("json", "deletedAt,omitempty")
+				deletedAt.setTag("gorm", "index")
+				it.Fields = append(it.Fields, deletedAt)
+			}
+
+			if m.ModelMutateHook != nil {
+				var err error
+				it, err = m.ModelMutateHook(schemaType, it)
+				if err != nil {
+					return nil, fmt.Errorf("generating model %s: %w", schemaType.Name, err)
+				}
 			}
 
 			b.Models = append(b.Models, it)
@@ -208,6 +445,46 @@ func (m *Plugin) MutateConfig(cfg *config.Config) error {
 	sort.Slice(b.Models, func(i, j int) bool { return b.Models[i].Name < b.Models[j].Name })
 	sort.Slice(b.Interfaces, func(i, j int) bool { return b.Interfaces[i].Name < b.Interfaces[j].Name })
 
+	objectsByName := make(map[string]*Object, len(b.Models))
+	for _, o := range b.Models {
+		objectsByName[o.Name] = o
+	}
+	for _, pr := range pendingRelations {
+		target := objectsByName[pr.targetName]
+		if target == nil {
+			continue
+		}
+		pk := PrimaryKeyField(target)
+
+		rel := &Relation{
+			Owner:    pr.owner.Name,
+			Field:    pr.field.Name,
+			Target:   target,
+			PKType:   pk.Type,
+			PKGoName: templates.ToGo(pk.Name),
+			PKColumn: GormColumn(pk),
+			IsList:   pr.isList,
+			Batch:    pr.batch,
+			Wait:     pr.wait,
+		}
+		b.Relations = append(b.Relations, rel)
+
+		loaderMethod := "Load"
+		if pr.isList {
+			loaderMethod = "LoadAll"
+		}
+		pr.field.LoaderHint = fmt.Sprintf(
+			"Resolve via For(ctx).%s.%s(ctx, ...) instead of a direct query.",
+			templates.ToGo(target.Name), loaderMethod,
+		)
+	}
+	sort.Slice(b.Relations, func(i, j int) bool {
+		if b.Relations[i].Owner != b.Relations[j].Owner {
+			return b.Relations[i].Owner < b.Relations[j].Owner
+		}
+		return b.Relations[i].Field < b.Relations[j].Field
+	})
+
 	for _, it := range b.Enums {
 		cfg.Models.Add(it.Name, cfg.Model.ImportPath()+"."+templates.ToGo(it.Name))
 	}
@@ -221,104 +498,653 @@ func (m *Plugin) MutateConfig(cfg *config.Config) error {
 		cfg.Models.Add(it, "github.com/99designs/gqlgen/graphql.String")
 	}
 
+	if m.MutateHook != nil {
+		b = m.MutateHook(b)
+	}
+
 	if len(b.Models) == 0 && len(b.Enums) == 0 && len(b.Interfaces) == 0 && len(b.Scalars) == 0 {
+		return nil, nil
+	}
+
+	return b, nil
+}
+
+// renderModels writes one Go file per interface, object, and enum in b,
+// instead of the single monolithic file gqlgen's own modelgen produces.
+// Object and interface files are written under m.ModelsDir (default: the
+// directory of cfg.Model.Filename); enum files are written under
+// m.EnumsDir (default: same as ModelsDir). All files share cfg.Model.Package.
+func renderModels(cfg *config.Config, b *ModelBuild, m *Plugin) error {
+	modelsDir := m.ModelsDir
+	if modelsDir == "" {
+		modelsDir = filepath.Dir(cfg.Model.Filename)
+	}
+	enumsDir := m.EnumsDir
+	if enumsDir == "" {
+		enumsDir = modelsDir
+	}
+
+	for _, it := range b.Interfaces {
+		if err := templates.Render(templates.Options{
+			PackageName:     cfg.Model.Package,
+			Filename:        filepath.Join(modelsDir, strings.ToLower(it.Name)+".go"),
+			Data:            it,
+			GeneratedHeader: true,
+			Packages:        cfg.Packages,
+			Template:        interfaceTemplate,
+		}); err != nil {
+			return fmt.Errorf("rendering interface %s: %w", it.Name, err)
+		}
+	}
+
+	for _, it := range b.Models {
+		if err := templates.Render(templates.Options{
+			PackageName:     cfg.Model.Package,
+			Filename:        filepath.Join(modelsDir, strings.ToLower(it.Name)+".go"),
+			Data:            it,
+			GeneratedHeader: true,
+			Packages:        cfg.Packages,
+			Funcs: template.FuncMap{
+				"renderTags": renderTags,
+			},
+			Template: modelTemplate,
+		}); err != nil {
+			return fmt.Errorf("rendering model %s: %w", it.Name, err)
+		}
+	}
+
+	for _, it := range b.Enums {
+		if err := templates.Render(templates.Options{
+			PackageName:     cfg.Model.Package,
+			Filename:        filepath.Join(enumsDir, strings.ToLower(it.Name)+".go"),
+			Data:            it,
+			GeneratedHeader: true,
+			Packages:        cfg.Packages,
+			Template:        enumTemplate,
+		}); err != nil {
+			return fmt.Errorf("rendering enum %s: %w", it.Name, err)
+		}
+	}
+
+	var keyed []*Object
+	for _, it := range b.Models {
+		if len(it.Keys) > 0 {
+			keyed = append(keyed, it)
+		}
+	}
+	if len(keyed) > 0 {
+		if err := templates.Render(templates.Options{
+			PackageName:     cfg.Model.Package,
+			Filename:        filepath.Join(modelsDir, "entity_gen.go"),
+			Data:            keyed,
+			GeneratedHeader: true,
+			Packages:        cfg.Packages,
+			Funcs: template.FuncMap{
+				"entityMethodName":   entityMethodName,
+				"fieldByGraphQLName": fieldByGraphQLName,
+				"keyParamName":       keyParamName,
+			},
+			Template: entityTemplate,
+		}); err != nil {
+			return fmt.Errorf("rendering federation entity interface: %w", err)
+		}
+	}
+
+	if err := renderDataloaders(cfg, b, modelsDir); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// renderDataloaders writes loaders_gen.go with one batching loader per
+// distinct relation target, plus a Loaders aggregate and http middleware
+// that installs a fresh set into the request context.
+func renderDataloaders(cfg *config.Config, b *ModelBuild, modelsDir string) error {
+	if len(b.Relations) == 0 {
 		return nil
 	}
 
+	seen := map[string]*Relation{}
+	var loaders []*Relation
+	for _, r := range b.Relations {
+		first, ok := seen[r.Target.Name]
+		if !ok {
+			seen[r.Target.Name] = r
+			loaders = append(loaders, r)
+			continue
+		}
+		if first.Batch != r.Batch || first.Wait != r.Wait {
+			return fmt.Errorf(
+				"todo: conflicting @dataloader batch/wait for %s: %s.%s wants (batch: %d, wait: %s), %s.%s wants (batch: %d, wait: %s)",
+				r.Target.Name,
+				first.Owner, first.Field, first.Batch, first.Wait,
+				r.Owner, r.Field, r.Batch, r.Wait,
+			)
+		}
+	}
+	sort.Slice(loaders, func(i, j int) bool { return loaders[i].Target.Name < loaders[j].Target.Name })
+
 	return templates.Render(templates.Options{
 		PackageName:     cfg.Model.Package,
-		Filename:        cfg.Model.Filename,
-		Data:            b,
+		Filename:        filepath.Join(modelsDir, "loaders_gen.go"),
+		Data:            loaders,
 		GeneratedHeader: true,
 		Packages:        cfg.Packages,
-		Template: `
-			{{ reserveImport "context"  }}
-			{{ reserveImport "fmt"  }}
-			{{ reserveImport "io"  }}
-			{{ reserveImport "strconv"  }}
-			{{ reserveImport "time"  }}
-			{{ reserveImport "sync"  }}
-			{{ reserveImport "errors"  }}
-			{{ reserveImport "bytes"  }}
-			
-			{{ reserveImport "github.com/vektah/gqlparser/v2" }}
-			{{ reserveImport "github.com/vektah/gqlparser/v2/ast" }}
-			{{ reserveImport "github.com/99designs/gqlgen/graphql" }}
-			{{ reserveImport "github.com/99designs/gqlgen/graphql/introspection" }}
-			
-			{{- range $model := .Interfaces }}
-				{{ with .Description }} {{.|prefixLines "// "}} {{ end }}
-				type {{.Name|go }} interface {
-					Is{{.Name|go }}()
-				}
+		Template:        loadersTemplate,
+	})
+}
+
+// entityMethodName derives the Entity interface method name for a key set,
+// e.g. objName "User" and keyFields ["id"] -> "FindUserByID"; keyFields
+// ["id", "organizationId"] -> "FindUserByIDAndOrganizationID".
+func entityMethodName(objName string, keyFields []string) string {
+	parts := make([]string, len(keyFields))
+	for i, kf := range keyFields {
+		parts[i] = templates.ToGo(kf)
+	}
+	return "Find" + objName + "By" + strings.Join(parts, "And")
+}
+
+// fieldByGraphQLName resolves a @key field name (as written in the schema)
+// to the already-built Field carrying its Go type, by comparing against
+// each field's Go name.
+func fieldByGraphQLName(o *Object, name string) *Field {
+	goName := templates.ToGo(name)
+	for _, f := range o.Fields {
+		if templates.ToGo(f.Name) == goName {
+			return f
+		}
+	}
+	return nil
+}
+
+// keyParamName turns a @key field's GraphQL name into a safe, unexported Go
+// parameter name for the Entity interface's methods, e.g. "organizationId"
+// -> "organizationID". Reserved words (a very ordinary field name like
+// "type") are prefixed with an underscore so the generated signature compiles.
+func keyParamName(name string) string {
+	goName := templates.ToGo(name)
+	r := []rune(goName)
+	r[0] = unicode.ToLower(r[0])
+	param := string(r)
+	if token.IsKeyword(param) {
+		param = "_" + param
+	}
+	return param
+}
+
+const interfaceTemplate = `
+	{{ with .Description }} {{.|prefixLines "// "}} {{ end }}
+	type {{.Name|go }} interface {
+		Is{{.Name|go }}()
+	}
+`
+
+const modelTemplate = `
+	{{ reserveImport "context" }}
+	{{ reserveImport "gorm.io/gorm" }}
+
+	{{with .Description }} {{.|prefixLines "// "}} {{end}}
+	type {{ .Name|go }} struct {
+		{{- range $field := .Fields }}
+			{{- with .Description }}
+				{{.|prefixLines "// "}}
+			{{- end}}
+			{{- with .LoaderHint }}
+				// {{.}}
+			{{- end}}
+			{{ $field.Name|go }} {{$field.Type | ref}}` + "`{{ renderTags $field.Tags }}`" + `
+		{{- end }}
+	}
+
+	{{- range $iface := .Implements }}
+		func ({{ $.Name|go }}) Is{{ $iface|go }}() {}
+	{{- end }}
+
+	{{- with .TableName }}
+		func ({{ $.Name|go }}) TableName() string {
+			return {{.|quote}}
+		}
+	{{- end }}
+
+	{{- if .Keys }}
+		// IsEntity marks {{ .Name|go }} as an Apollo Federation entity, resolvable
+		// by the gateway via the Entity interface in entity_gen.go.
+		func ({{ $.Name|go }}) IsEntity() {}
+
+		// __resolveReference is the gateway's __resolveReference hook for
+		// {{ .Name|go }}: it's called with a partial {{ .Name|go }} carrying only
+		// the @key fields decoded from the representation, and should return
+		// the fully resolved entity. This stub returns the partial value
+		// unchanged; back it with a real lookup (e.g. one of the Entity
+		// interface's Find{{ .Name|go }}By... methods in entity_gen.go) before
+		// relying on it.
+		func (o {{ $.Name|go }}) __resolveReference(ctx context.Context) (*{{ $.Name|go }}, error) {
+			return &o, nil
+		}
+	{{- end }}
+`
+
+const entityTemplate = `
+	{{ reserveImport "context" }}
+
+	// Entity lists the federation entity-resolution methods the gateway calls
+	// (via __resolveReference) for each @key-annotated type.
+	type Entity interface {
+		{{- range $o := . }}
+			{{- range $key := $o.Keys }}
+		{{ entityMethodName ($o.Name|go) $key }}(ctx context.Context{{ range $f := $key }}, {{ keyParamName $f }} {{ (fieldByGraphQLName $o $f).Type | ref }}{{ end }}) (*{{ $o.Name|go }}, error)
 			{{- end }}
-			
-			{{ range $model := .Models }}
-				{{with .Description }} {{.|prefixLines "// "}} {{end}}
-				type {{ .Name|go }} struct {
-					{{- range $field := .Fields }}
-						{{- with .Description }}
-							{{.|prefixLines "// "}}
-						{{- end}}
-						{{ $field.Name|go }} {{$field.Type | ref}}` + "`{{$field.Tag}} {{$field.Gorm}}`" + `
-					{{- end }}
+		{{- end }}
+	}
+`
+
+const loadersTemplate = `
+	{{ reserveImport "context" }}
+	{{ reserveImport "net/http" }}
+	{{ reserveImport "sync" }}
+	{{ reserveImport "time" }}
+	{{ reserveImport "gorm.io/gorm" }}
+
+	{{ range $rel := . }}
+		// {{ $rel.Target.Name|go }}Loader batches and caches {{ $rel.Target.Name|go }}
+		// lookups by primary key to avoid N+1 queries when resolving
+		// {{ $rel.Target.Name|go }}-typed fields.
+		type {{ $rel.Target.Name|go }}Loader struct {
+			// Fetch batch-loads rows for a set of keys; set by New{{ $rel.Target.Name|go }}Loader.
+			Fetch func(ctx context.Context, keys []{{ $rel.PKType | ref }}) (map[{{ $rel.PKType | ref }}]*{{ $rel.Target.Name|go }}, error)
+			// Wait is how long to collect keys into a batch before calling Fetch.
+			Wait time.Duration
+			// MaxBatch is the largest batch sent to Fetch in one call. 0 means no limit.
+			MaxBatch int
+			// TTL is how long a fetched value stays cached before it is fetched
+			// again. 0 means cached values never expire on their own.
+			TTL time.Duration
+
+			mu    sync.Mutex
+			cache map[{{ $rel.PKType | ref }}]*{{ $rel.Target.Name|go }}LoaderEntry
+			batch *{{ $rel.Target.Name|go }}LoaderBatch
+		}
+
+		type {{ $rel.Target.Name|go }}LoaderEntry struct {
+			value     *{{ $rel.Target.Name|go }}
+			expiresAt time.Time
+		}
+
+		type {{ $rel.Target.Name|go }}LoaderBatch struct {
+			keys      []{{ $rel.PKType | ref }}
+			results   map[{{ $rel.PKType | ref }}]*{{ $rel.Target.Name|go }}
+			err       error
+			done      chan struct{}
+			sendOnce  sync.Once
+			closeOnce sync.Once
+		}
+
+		func (b *{{ $rel.Target.Name|go }}LoaderBatch) finish() {
+			b.closeOnce.Do(func() { close(b.done) })
+		}
+
+		// New{{ $rel.Target.Name|go }}Loader builds a {{ $rel.Target.Name|go }}Loader with
+		// the given batch-fetch function and this relation's configured batch
+		// size and wait window.
+		func New{{ $rel.Target.Name|go }}Loader(fetch func(ctx context.Context, keys []{{ $rel.PKType | ref }}) (map[{{ $rel.PKType | ref }}]*{{ $rel.Target.Name|go }}, error)) *{{ $rel.Target.Name|go }}Loader {
+			return &{{ $rel.Target.Name|go }}Loader{
+				Fetch:    fetch,
+				Wait:     time.Duration({{ $rel.Wait.Nanoseconds }}),
+				MaxBatch: {{ $rel.Batch }},
+				cache:    map[{{ $rel.PKType | ref }}]*{{ $rel.Target.Name|go }}LoaderEntry{},
+			}
+		}
+
+		// Load fetches a single {{ $rel.Target.Name|go }} by key, batching this call
+		// together with any other Load calls made within the loader's Wait window.
+		func (l *{{ $rel.Target.Name|go }}Loader) Load(ctx context.Context, key {{ $rel.PKType | ref }}) (*{{ $rel.Target.Name|go }}, error) {
+			l.mu.Lock()
+			if l.cache == nil {
+				l.cache = map[{{ $rel.PKType | ref }}]*{{ $rel.Target.Name|go }}LoaderEntry{}
+			}
+			if entry, ok := l.cache[key]; ok && (l.TTL == 0 || time.Now().Before(entry.expiresAt)) {
+				l.mu.Unlock()
+				return entry.value, nil
+			}
+
+			b := l.batch
+			if b == nil {
+				b = &{{ $rel.Target.Name|go }}LoaderBatch{done: make(chan struct{})}
+				l.batch = b
+				go l.dispatch(b)
+			}
+			b.keys = append(b.keys, key)
+			full := l.MaxBatch > 0 && len(b.keys) >= l.MaxBatch
+			if full {
+				l.batch = nil
+			}
+			l.mu.Unlock()
+
+			if full {
+				go l.send(b)
+			}
+
+			<-b.done
+			if b.err != nil {
+				return nil, b.err
+			}
+			return b.results[key], nil
+		}
+
+		// LoadAll fetches several {{ $rel.Target.Name|go }} rows by key.
+		func (l *{{ $rel.Target.Name|go }}Loader) LoadAll(ctx context.Context, keys []{{ $rel.PKType | ref }}) ([]*{{ $rel.Target.Name|go }}, error) {
+			out := make([]*{{ $rel.Target.Name|go }}, len(keys))
+			for i, key := range keys {
+				v, err := l.Load(ctx, key)
+				if err != nil {
+					return nil, err
 				}
-			
-				{{- range $iface := .Implements }}
-					func ({{ $model.Name|go }}) Is{{ $iface|go }}() {}
-				{{- end }}
-			{{- end}}
-			
-			{{ range $enum := .Enums }}
-				{{ with .Description }} {{.|prefixLines "// "}} {{end}}
-				type {{.Name|go }} string
-				const (
-				{{- range $value := .Values}}
-					{{- with .Description}}
-						{{.|prefixLines "// "}}
-					{{- end}}
-					{{ $enum.Name|go }}{{ .Name|go }} {{$enum.Name|go }} = {{.Name|quote}}
-				{{- end }}
-				)
-			
-				var All{{.Name|go }} = []{{ .Name|go }}{
-				{{- range $value := .Values}}
-					{{$enum.Name|go }}{{ .Name|go }},
-				{{- end }}
+				out[i] = v
+			}
+			return out, nil
+		}
+
+		func (l *{{ $rel.Target.Name|go }}Loader) dispatch(b *{{ $rel.Target.Name|go }}LoaderBatch) {
+			time.Sleep(l.Wait)
+			l.send(b)
+		}
+
+		func (l *{{ $rel.Target.Name|go }}Loader) send(b *{{ $rel.Target.Name|go }}LoaderBatch) {
+			b.sendOnce.Do(func() {
+				l.mu.Lock()
+				if l.batch == b {
+					l.batch = nil
 				}
-			
-				func (e {{.Name|go }}) IsValid() bool {
-					switch e {
-					case {{ range $index, $element := .Values}}{{if $index}},{{end}}{{ $enum.Name|go }}{{ $element.Name|go }}{{end}}:
-						return true
+				keys := b.keys
+				l.mu.Unlock()
+
+				results, err := l.Fetch(context.Background(), keys)
+
+				l.mu.Lock()
+				b.results, b.err = results, err
+				if err == nil {
+					now := time.Now()
+					for k, v := range results {
+						var expiresAt time.Time
+						if l.TTL > 0 {
+							expiresAt = now.Add(l.TTL)
+						}
+						l.cache[k] = &{{ $rel.Target.Name|go }}LoaderEntry{value: v, expiresAt: expiresAt}
 					}
-					return false
-				}
-			
-				func (e {{.Name|go }}) String() string {
-					return string(e)
 				}
-			
-				func (e *{{.Name|go }}) UnmarshalGQL(v interface{}) error {
-					str, ok := v.(string)
-					if !ok {
-						return fmt.Errorf("enums must be strings")
-					}
-			
-					*e = {{ .Name|go }}(str)
-					if !e.IsValid() {
-						return fmt.Errorf("%s is not a valid {{ .Name }}", str)
-					}
-					return nil
+				l.mu.Unlock()
+				b.finish()
+			})
+		}
+	{{ end }}
+
+	// Loaders aggregates every per-request dataloader produced for
+	// @isDatabaseField relation fields.
+	type Loaders struct {
+		{{- range $rel := . }}
+		{{ $rel.Target.Name|go }} *{{ $rel.Target.Name|go }}Loader
+		{{- end }}
+	}
+
+	// NewLoaders builds a fresh set of dataloaders backed by db, fetching
+	// each target type's rows in primary-key batches. Call this once per
+	// request - loaders must not be shared across requests.
+	func NewLoaders(db *gorm.DB) *Loaders {
+		return &Loaders{
+			{{- range $rel := . }}
+			{{ $rel.Target.Name|go }}: New{{ $rel.Target.Name|go }}Loader(func(ctx context.Context, keys []{{ $rel.PKType | ref }}) (map[{{ $rel.PKType | ref }}]*{{ $rel.Target.Name|go }}, error) {
+				var rows []*{{ $rel.Target.Name|go }}
+				if err := db.WithContext(ctx).Find(&rows, "{{ $rel.PKColumn }} IN ?", keys).Error; err != nil {
+					return nil, err
 				}
-			
-				func (e {{.Name|go }}) MarshalGQL(w io.Writer) {
-					fmt.Fprint(w, strconv.Quote(e.String()))
+				out := make(map[{{ $rel.PKType | ref }}]*{{ $rel.Target.Name|go }}, len(rows))
+				for _, row := range rows {
+					out[row.{{ $rel.PKGoName }}] = row
 				}
-			
+				return out, nil
+			}),
 			{{- end }}
-		`,
-	})
+		}
+	}
+
+	type loadersCtxKey struct{}
+
+	// Middleware installs a fresh set of dataloaders into the request context
+	// of every HTTP request, so resolvers can fetch them with For(ctx).
+	func Middleware(db *gorm.DB) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), loadersCtxKey{}, NewLoaders(db))
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		}
+	}
+
+	// For retrieves the request's Loaders, installed by Middleware.
+	func For(ctx context.Context) *Loaders {
+		loaders, _ := ctx.Value(loadersCtxKey{}).(*Loaders)
+		return loaders
+	}
+`
+
+const enumTemplate = `
+	{{ reserveImport "fmt"  }}
+	{{ reserveImport "io"  }}
+	{{ reserveImport "strconv"  }}
+
+	{{ with .Description }} {{.|prefixLines "// "}} {{end}}
+	type {{.Name|go }} string
+	const (
+	{{- range $value := .Values}}
+		{{- with .Description}}
+			{{.|prefixLines "// "}}
+		{{- end}}
+		{{ $.Name|go }}{{ .Name|go }} {{$.Name|go }} = {{.Name|quote}}
+	{{- end }}
+	)
+
+	var All{{.Name|go }} = []{{ .Name|go }}{
+	{{- range $value := .Values}}
+		{{$.Name|go }}{{ .Name|go }},
+	{{- end }}
+	}
+
+	func (e {{.Name|go }}) IsValid() bool {
+		switch e {
+		case {{ range $index, $element := .Values}}{{if $index}},{{end}}{{ $.Name|go }}{{ $element.Name|go }}{{end}}:
+			return true
+		}
+		return false
+	}
+
+	func (e {{.Name|go }}) String() string {
+		return string(e)
+	}
+
+	func (e *{{.Name|go }}) UnmarshalGQL(v interface{}) error {
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("enums must be strings")
+		}
+
+		*e = {{ .Name|go }}(str)
+		if !e.IsValid() {
+			return fmt.Errorf("%s is not a valid {{ .Name }}", str)
+		}
+		return nil
+	}
+
+	func (e {{.Name|go }}) MarshalGQL(w io.Writer) {
+		fmt.Fprint(w, strconv.Quote(e.String()))
+	}
+`
+
+// gormTagFromDirective builds the `gorm` struct tag value for a field from
+// its @isDatabaseField arguments, composing column name, SQL type, size,
+// constraints, indexes, and association hints into GORM's semicolon
+// separated tag syntax. It returns "" when the field carries no directive.
+func gormTagFromDirective(fieldName string, directive *ast.Directive) string {
+	if directive == nil {
+		return ""
+	}
+
+	column := fieldName
+	if arg := directive.Arguments.ForName("fieldName"); arg != nil && arg.Value.Raw != "" {
+		column = arg.Value.Raw
+	}
+
+	parts := []string{"column:" + column}
+
+	if arg := directive.Arguments.ForName("type"); arg != nil {
+		parts = append(parts, "type:"+arg.Value.Raw)
+	}
+	if arg := directive.Arguments.ForName("size"); arg != nil {
+		parts = append(parts, "size:"+arg.Value.Raw)
+	}
+	if arg := directive.Arguments.ForName("primaryKey"); arg != nil && arg.Value.Raw == "true" {
+		parts = append(parts, "primaryKey")
+	}
+	if arg := directive.Arguments.ForName("autoIncrement"); arg != nil && arg.Value.Raw == "true" {
+		parts = append(parts, "autoIncrement")
+	}
+	if arg := directive.Arguments.ForName("notNull"); arg != nil && arg.Value.Raw == "true" {
+		parts = append(parts, "not null")
+	}
+	if arg := directive.Arguments.ForName("default"); arg != nil {
+		parts = append(parts, "default:"+arg.Value.Raw)
+	}
+	if arg := directive.Arguments.ForName("uniqueIndex"); arg != nil {
+		if arg.Value.Raw == "" {
+			parts = append(parts, "uniqueIndex")
+		} else {
+			parts = append(parts, "uniqueIndex:"+arg.Value.Raw)
+		}
+	}
+	if arg := directive.Arguments.ForName("index"); arg != nil {
+		if arg.Value.Raw == "" {
+			parts = append(parts, "index")
+		} else {
+			parts = append(parts, "index:"+arg.Value.Raw)
+		}
+	}
+	if arg := directive.Arguments.ForName("foreignKey"); arg != nil {
+		parts = append(parts, "foreignKey:"+arg.Value.Raw)
+	}
+	if arg := directive.Arguments.ForName("references"); arg != nil {
+		parts = append(parts, "references:"+arg.Value.Raw)
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// applyGoTagDirectives merges any number of repeatable @goTag(key, value)
+// directives declared on a field into its tag set. This lets a schema
+// attach validation tags, custom json names, protobuf tags, etc. without
+// forking the plugin.
+func applyGoTagDirectives(f *Field, field *ast.FieldDefinition) {
+	for _, d := range field.Directives {
+		if d.Name != "goTag" {
+			continue
+		}
+
+		keyArg := d.Arguments.ForName("key")
+		if keyArg == nil {
+			continue
+		}
+
+		value := ""
+		if valueArg := d.Arguments.ForName("value"); valueArg != nil {
+			value = valueArg.Value.Raw
+		}
+
+		f.setTag(keyArg.Value.Raw, value)
+	}
+}
+
+// pendingRelation records a relation field discovered mid-scan, before the
+// target Object it points to is necessarily known to exist in b.Models.
+type pendingRelation struct {
+	owner      *Object
+	field      *Field
+	targetName string
+	isList     bool
+	batch      int
+	wait       time.Duration
+}
+
+// relationTarget reports whether a field's type is another @isDatabaseField
+// object (or a list of one), returning that object's GraphQL type name.
+func relationTarget(fieldDef *ast.Definition, field *ast.FieldDefinition) (targetName string, isList bool, ok bool) {
+	if fieldDef == nil || fieldDef.Kind != ast.Object {
+		return "", false, false
+	}
+	if fieldDef.Directives.ForName("isDatabaseField") == nil {
+		return "", false, false
+	}
+	return fieldDef.Name, field.Type.Elem != nil, true
+}
+
+// dataloaderDirective reads a field's @dataloader(enable, batch, wait)
+// arguments, falling back to sensible defaults when the directive (or one
+// of its arguments) is absent. It errors when batch/wait are present but
+// malformed, rather than silently keeping the default.
+func dataloaderDirective(field *ast.FieldDefinition) (enabled bool, batch int, wait time.Duration, err error) {
+	enabled, batch, wait = true, 100, 16*time.Millisecond
+
+	d := field.Directives.ForName("dataloader")
+	if d == nil {
+		return enabled, batch, wait, nil
+	}
+	if arg := d.Arguments.ForName("enable"); arg != nil && arg.Value.Raw == "false" {
+		enabled = false
+	}
+	if arg := d.Arguments.ForName("batch"); arg != nil {
+		n, convErr := strconv.Atoi(arg.Value.Raw)
+		if convErr != nil {
+			return false, 0, 0, fmt.Errorf("field %s: @dataloader(batch: %q) is not a valid integer: %w", field.Name, arg.Value.Raw, convErr)
+		}
+		batch = n
+	}
+	if arg := d.Arguments.ForName("wait"); arg != nil {
+		w, convErr := time.ParseDuration(arg.Value.Raw)
+		if convErr != nil {
+			return false, 0, 0, fmt.Errorf("field %s: @dataloader(wait: %q) is not a valid duration: %w", field.Name, arg.Value.Raw, convErr)
+		}
+		wait = w
+	}
+	return enabled, batch, wait, nil
+}
+
+// PrimaryKeyField picks the field that identifies a row of o: the field
+// carrying `gorm:"...primaryKey..."`, falling back to a field named ID,
+// falling back to the first declared field.
+func PrimaryKeyField(o *Object) *Field {
+	for _, f := range o.Fields {
+		if strings.Contains(f.Tags["gorm"], "primaryKey") {
+			return f
+		}
+	}
+	for _, f := range o.Fields {
+		if f.Name == "ID" {
+			return f
+		}
+	}
+	return o.Fields[0]
+}
+
+// GormColumn extracts the column name from a field's gorm tag, falling
+// back to its lowercased field name when the tag has none. Sibling plugins
+// (e.g. resolvergen) use this to key queries on the same column the
+// generated struct tag declares.
+func GormColumn(f *Field) string {
+	for _, part := range strings.Split(f.Tags["gorm"], ";") {
+		if strings.HasPrefix(part, "column:") {
+			return strings.TrimPrefix(part, "column:")
+		}
+	}
+	return strings.ToLower(f.Name)
 }
 
 func isStruct(t types.Type) bool {
@@ -335,7 +1161,43 @@ func (r *Plugin) InjectSourceEarly() *ast.Source {
 				name: String
 			  ) on INPUT_FIELD_DEFINITION | FIELD_DEFINITION
 
-			  directive @isDatabaseField(fieldName: String) on OBJECT | FIELD_DEFINITION
+			  directive @isDatabaseField(
+				fieldName: String
+				type: String
+				size: Int
+				primaryKey: Boolean
+				autoIncrement: Boolean
+				notNull: Boolean
+				default: String
+				uniqueIndex: String
+				index: String
+				foreignKey: String
+				references: String
+			  ) on OBJECT | FIELD_DEFINITION
+
+			  directive @gormModel(
+				tableName: String
+				softDelete: Boolean
+			  ) on OBJECT
+
+			  directive @goTag(
+				key: String!
+				value: String
+			  ) repeatable on FIELD_DEFINITION
+
+			  directive @key(fields: String!) repeatable on OBJECT
+
+			  directive @extends on OBJECT | INTERFACE
+
+			  directive @external on FIELD_DEFINITION
+
+			  directive @requires(fields: String!) on FIELD_DEFINITION
+
+			  directive @dataloader(
+				enable: Boolean
+				batch: Int
+				wait: String
+			  ) on FIELD_DEFINITION
 
 			scalar Time
 		`,